@@ -0,0 +1,103 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	olivere7 "github.com/olivere/elastic/v7"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner"
+)
+
+// esIndicesClient adapts an olivere/elastic v7 client to cleaner.IndicesClient.
+type esIndicesClient struct {
+	client *olivere7.Client
+}
+
+func newESIndicesClient(client *olivere7.Client) *esIndicesClient {
+	return &esIndicesClient{client: client}
+}
+
+func (e *esIndicesClient) GetIndices(ctx context.Context) ([]cleaner.Index, error) {
+	settings, err := e.client.IndexGetSettings().Index("*").Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliasesByIndex, err := e.client.Aliases().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]cleaner.Index, 0, len(settings))
+	for name, setting := range settings {
+		var aliases []string
+		if index, ok := aliasesByIndex.Indices[name]; ok {
+			for _, alias := range index.Aliases {
+				aliases = append(aliases, alias.AliasName)
+			}
+		}
+		creationTime, err := parseCreationDate(creationDateOf(setting.Settings))
+		if err != nil {
+			log.Printf("skipping index %s: %v", name, err)
+			continue
+		}
+		indices = append(indices, cleaner.Index{
+			Name:         name,
+			CreationTime: creationTime,
+			Aliases:      aliases,
+		})
+	}
+	return indices, nil
+}
+
+func (e *esIndicesClient) DeleteIndex(ctx context.Context, name string) error {
+	_, err := e.client.DeleteIndex(name).Do(ctx)
+	return err
+}
+
+// Index implements esreporter.Indexer, so an esIndicesClient can double as
+// the sink the ES-writing reporter observer writes run summaries through.
+func (e *esIndicesClient) Index(ctx context.Context, index string, document interface{}) error {
+	_, err := e.client.Index().Index(index).BodyJson(document).Do(ctx)
+	return err
+}
+
+// creationDateOf extracts the "index.creation_date" setting, returning "" if
+// the settings document doesn't have the expected shape (e.g. certain
+// system/internal indices).
+func creationDateOf(settings map[string]interface{}) string {
+	index, ok := settings["index"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	creationDate, _ := index["creation_date"].(string)
+	return creationDate
+}
+
+// parseCreationDate parses an "index.creation_date" setting (epoch millis as
+// a string). It returns an error rather than the zero time on failure, so
+// that callers skip the index instead of treating it as infinitely old and
+// deleting it.
+func parseCreationDate(creationDate string) (time.Time, error) {
+	millis, err := strconv.ParseInt(creationDate, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid creation_date %q: %w", creationDate, err)
+	}
+	return time.UnixMilli(millis), nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command jaeger-es-index-cleaner is a thin CLI wrapper around
+// pkg/es/cleaner. It preserves the contract of the
+// jaegertracing/jaeger-es-index-cleaner Docker image: positional args
+// `<num-of-days> <es-url>`, and ROLLOVER/ARCHIVE/INDEX_PREFIX/TIMEOUT/
+// USE_OPENSEARCH environment variables. It additionally honors
+// METRICS_BACKEND/METRICS_PUSHGATEWAY_URL and ES_REPORTER_ENABLED/
+// ES_REPORTER_TAGS to report cleanup activity through the cleaner's
+// Observer hook; see wireObservers for details.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	olivere7 "github.com/olivere/elastic/v7"
+	opensearch "github.com/opensearch-project/opensearch-go"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner"
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/cleanermetrics"
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/cleanermetrics/cleanermetricsprom"
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/esreporter"
+)
+
+func main() {
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 2 {
+		log.Fatal("usage: jaeger-es-index-cleaner <num-of-days> <es-url>")
+	}
+
+	days, err := strconv.Atoi(args[0])
+	if err != nil {
+		log.Fatalf("invalid number of days %q: %v", args[0], err)
+	}
+	esURL := args[1]
+
+	var indicesClient cleaner.IndicesClient
+	if os.Getenv("USE_OPENSEARCH") == "true" {
+		cl, err := opensearch.NewClient(opensearch.Config{Addresses: []string{esURL}})
+		if err != nil {
+			log.Fatalf("failed to create OpenSearch client: %v", err)
+		}
+		indicesClient = newOpenSearchIndicesClient(cl)
+	} else {
+		cl, err := olivere7.NewClient(olivere7.SetURL(esURL), olivere7.SetSniff(false))
+		if err != nil {
+			log.Fatalf("failed to create Elasticsearch client: %v", err)
+		}
+		indicesClient = newESIndicesClient(cl)
+	}
+
+	cfg := cleaner.Config{
+		Rollover:    os.Getenv("ROLLOVER") == "true",
+		Archive:     os.Getenv("ARCHIVE") == "true",
+		IndexPrefix: os.Getenv("INDEX_PREFIX"),
+		MaxAge:      time.Duration(days) * 24 * time.Hour,
+	}
+	if cfg.IndexPrefix != "" {
+		cfg.IndexPrefix += "-"
+	}
+	if timeout := os.Getenv("TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.Fatalf("invalid TIMEOUT %q: %v", timeout, err)
+		}
+		cfg.Timeout = d
+	}
+
+	observer, pushMetrics := wireObservers(indicesClient)
+	cfg.Observer = observer
+
+	report, err := cleaner.New(indicesClient).Run(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("index cleanup failed: %v", err)
+	}
+	fmt.Printf("deleted %d indices, skipped %d indices, %d errors\n",
+		len(report.Deleted), len(report.Skipped), len(report.Errors))
+	for index, err := range report.Errors {
+		log.Printf("failed to delete index %s: %v", index, err)
+	}
+	if err := pushMetrics(); err != nil {
+		log.Printf("failed to push metrics to Pushgateway: %v", err)
+	}
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+// wireObservers builds the cleaner.Observer used for this run from the
+// METRICS_BACKEND/METRICS_PUSHGATEWAY_URL and ES_REPORTER_ENABLED/
+// ES_REPORTER_TAGS environment variables, so operators can dashboard cleanup
+// activity (Prometheus, an ES self-reporting index) without editing code. It
+// returns nil when neither sink is enabled, and a pushMetrics func (a no-op
+// unless METRICS_PUSHGATEWAY_URL is set) to call once the run has completed.
+func wireObservers(indicesClient cleaner.IndicesClient) (cleaner.Observer, func() error) {
+	var observers []cleaner.Observer
+	pushMetrics := func() error { return nil }
+
+	if os.Getenv("METRICS_BACKEND") == "prometheus" {
+		factory := cleanermetricsprom.NewFactory()
+		observers = append(observers, cleanermetrics.NewObserver(factory))
+		if gatewayURL := os.Getenv("METRICS_PUSHGATEWAY_URL"); gatewayURL != "" {
+			pushMetrics = func() error {
+				return push.New(gatewayURL, "jaeger_es_index_cleaner").Gatherer(factory.Registry()).Push()
+			}
+		}
+	}
+
+	if os.Getenv("ES_REPORTER_ENABLED") == "true" {
+		indexer, ok := indicesClient.(esreporter.Indexer)
+		if !ok {
+			log.Printf("ES_REPORTER_ENABLED is set but the configured client cannot index documents")
+		} else {
+			var opts []esreporter.Option
+			if tags := parseTags(os.Getenv("ES_REPORTER_TAGS")); len(tags) > 0 {
+				opts = append(opts, esreporter.WithTags(tags))
+			}
+			observers = append(observers, esreporter.NewReporter(indexer, opts...))
+		}
+	}
+
+	if len(observers) == 0 {
+		return nil, pushMetrics
+	}
+	return cleaner.NewMultiObserver(observers...), pushMetrics
+}
+
+// parseTags parses a comma-separated key=value list, e.g.
+// "cluster=prod,jaeger_version=1.2.3", as used by ES_REPORTER_TAGS. Entries
+// without an "=" are skipped.
+func parseTags(s string) map[string]string {
+	if s == "" {
+		return nil
+	}
+	tags := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return tags
+}
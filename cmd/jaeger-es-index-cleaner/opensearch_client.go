@@ -0,0 +1,104 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	opensearch "github.com/opensearch-project/opensearch-go"
+	opensearchapi "github.com/opensearch-project/opensearch-go/opensearchapi"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner"
+)
+
+// openSearchIndicesClient adapts an opensearch-go client to cleaner.IndicesClient.
+type openSearchIndicesClient struct {
+	client *opensearch.Client
+}
+
+func newOpenSearchIndicesClient(client *opensearch.Client) *openSearchIndicesClient {
+	return &openSearchIndicesClient{client: client}
+}
+
+func (o *openSearchIndicesClient) GetIndices(ctx context.Context) ([]cleaner.Index, error) {
+	res, err := opensearchapi.IndicesGetRequest{Index: []string{"*"}}.Do(ctx, o.client)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var body map[string]struct {
+		Aliases  map[string]interface{} `json:"aliases"`
+		Settings struct {
+			Index struct {
+				CreationDate string `json:"creation_date"`
+			} `json:"index"`
+		} `json:"settings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	indices := make([]cleaner.Index, 0, len(body))
+	for name, entry := range body {
+		aliases := make([]string, 0, len(entry.Aliases))
+		for alias := range entry.Aliases {
+			aliases = append(aliases, alias)
+		}
+		creationTime, err := parseCreationDate(entry.Settings.Index.CreationDate)
+		if err != nil {
+			log.Printf("skipping index %s: %v", name, err)
+			continue
+		}
+		indices = append(indices, cleaner.Index{
+			Name:         name,
+			CreationTime: creationTime,
+			Aliases:      aliases,
+		})
+	}
+	return indices, nil
+}
+
+func (o *openSearchIndicesClient) DeleteIndex(ctx context.Context, name string) error {
+	res, err := opensearchapi.IndicesDeleteRequest{Index: []string{name}}.Do(ctx, o.client)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return nil
+}
+
+// Index implements esreporter.Indexer, so an openSearchIndicesClient can
+// double as the sink the ES-writing reporter observer writes run summaries
+// through.
+func (o *openSearchIndicesClient) Index(ctx context.Context, index string, document interface{}) error {
+	body, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	res, err := opensearchapi.IndexRequest{Index: index, Body: bytes.NewReader(body)}.Do(ctx, o.client)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("index request to %s failed: %s", index, res.String())
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cleaner implements the Elasticsearch/OpenSearch index cleanup
+// logic in-process, so that callers other than the jaeger-es-index-cleaner
+// Docker image (e.g. integration tests or an embedded collector job) can
+// run it without shelling out to docker.
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Index describes a single Elasticsearch/OpenSearch index as seen by the Cleaner.
+type Index struct {
+	// Name is the full index name, including any prefix.
+	Name string
+	// CreationTime is when the index was created, used to compute its age.
+	CreationTime time.Time
+	// Aliases are the aliases currently pointing at this index, used to
+	// recognize rollover read/write aliases.
+	Aliases []string
+}
+
+// IndicesClient is the subset of an Elasticsearch/OpenSearch REST client the
+// Cleaner needs. Implementations wrap the version-specific client (v5/v6/v7,
+// or OpenSearch) used elsewhere in this repo.
+type IndicesClient interface {
+	GetIndices(ctx context.Context) ([]Index, error)
+	DeleteIndex(ctx context.Context, name string) error
+}
+
+// Config controls a single cleanup run.
+type Config struct {
+	// Rollover indicates the indices being cleaned are rollover indices
+	// (read/write aliases) rather than the daily-bucketed ones.
+	Rollover bool
+	// Archive indicates the span-archive indices should also be cleaned.
+	Archive bool
+	// IndexPrefix is prepended to the jaeger-* index names, e.g. "custom-jaeger-span".
+	IndexPrefix string
+	// MaxAge is how old an index must be, relative to Clock.Now(), before it is deleted.
+	MaxAge time.Duration
+	// Timeout bounds the whole Run call, including listing and deleting indices.
+	Timeout time.Duration
+	// Clock is used to compute index age; defaults to the real wall clock.
+	Clock func() time.Time
+	// Observer is notified of cleanup activity as Run progresses. Defaults
+	// to NopObserver when nil.
+	Observer Observer
+}
+
+// Report summarizes the outcome of a single Run.
+type Report struct {
+	// Deleted lists the indices that were removed.
+	Deleted []string
+	// Skipped lists the indices that were considered but kept (too young, or excluded by Config).
+	Skipped []string
+	// Errors maps an index name to the error encountered while deleting it.
+	Errors map[string]error
+}
+
+// Cleaner removes Elasticsearch/OpenSearch indices older than a configured age.
+type Cleaner struct {
+	client IndicesClient
+}
+
+// New creates a Cleaner that operates through the given client.
+func New(client IndicesClient) *Cleaner {
+	return &Cleaner{client: client}
+}
+
+// Run lists the indices visible to the client, deletes the ones that are
+// older than cfg.MaxAge and match cfg, and returns a Report describing what
+// happened.
+func (c *Cleaner) Run(ctx context.Context, cfg Config) (Report, error) {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+	now := time.Now
+	if cfg.Clock != nil {
+		now = cfg.Clock
+	}
+	observer := cfg.Observer
+	if observer == nil {
+		observer = NopObserver{}
+	}
+	start := now()
+
+	indices, err := c.client.GetIndices(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to list indices: %w", err)
+	}
+
+	report := Report{Errors: map[string]error{}}
+	for _, index := range indices {
+		if !strings.HasPrefix(index.Name, cfg.IndexPrefix) {
+			continue
+		}
+		observer.IndexEvaluated(index)
+		if isArchiveIndex(index) && !cfg.Archive {
+			report.Skipped = append(report.Skipped, index.Name)
+			continue
+		}
+		if isRolloverIndex(index) != cfg.Rollover {
+			report.Skipped = append(report.Skipped, index.Name)
+			continue
+		}
+		if now().Sub(index.CreationTime) < cfg.MaxAge {
+			report.Skipped = append(report.Skipped, index.Name)
+			continue
+		}
+		if err := c.client.DeleteIndex(ctx, index.Name); err != nil {
+			report.Errors[index.Name] = err
+			continue
+		}
+		observer.IndexDeleted(index)
+		if cfg.Rollover {
+			observer.RolloverPerformed(index)
+		}
+		report.Deleted = append(report.Deleted, index.Name)
+	}
+	observer.RunCompleted(RunSummary{
+		Duration: now().Sub(start),
+		Deleted:  len(report.Deleted),
+		Errors:   len(report.Errors),
+	})
+	return report, nil
+}
+
+func isArchiveIndex(index Index) bool {
+	return strings.Contains(index.Name, "-archive")
+}
+
+func isRolloverIndex(index Index) bool {
+	for _, alias := range index.Aliases {
+		if strings.HasSuffix(alias, "-write") || strings.HasSuffix(alias, "-read") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIndicesClient struct {
+	indices    []Index
+	deleted    []string
+	deleteErrs map[string]error
+}
+
+func (f *fakeIndicesClient) GetIndices(context.Context) ([]Index, error) {
+	return f.indices, nil
+}
+
+func (f *fakeIndicesClient) DeleteIndex(_ context.Context, name string) error {
+	if err, ok := f.deleteErrs[name]; ok {
+		return err
+	}
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+func TestCleaner_Run(t *testing.T) {
+	now := time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)
+	client := &fakeIndicesClient{
+		indices: []Index{
+			{Name: "jaeger-span-2021-01-01", CreationTime: now.Add(-9 * 24 * time.Hour)},
+			{Name: "jaeger-span-2021-01-09", CreationTime: now.Add(-1 * 24 * time.Hour)},
+			{Name: "jaeger-span-archive", CreationTime: now.Add(-30 * 24 * time.Hour)},
+		},
+	}
+
+	report, err := New(client).Run(context.Background(), Config{
+		IndexPrefix: "jaeger-",
+		MaxAge:      7 * 24 * time.Hour,
+		Clock:       func() time.Time { return now },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jaeger-span-2021-01-01"}, report.Deleted)
+	assert.ElementsMatch(t, []string{"jaeger-span-2021-01-09", "jaeger-span-archive"}, report.Skipped)
+	assert.Empty(t, report.Errors)
+}
+
+func TestCleaner_Run_Archive(t *testing.T) {
+	now := time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)
+	client := &fakeIndicesClient{
+		indices: []Index{
+			{Name: "jaeger-span-archive", CreationTime: now.Add(-30 * 24 * time.Hour)},
+		},
+	}
+
+	report, err := New(client).Run(context.Background(), Config{
+		IndexPrefix: "jaeger-",
+		Archive:     true,
+		MaxAge:      7 * 24 * time.Hour,
+		Clock:       func() time.Time { return now },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jaeger-span-archive"}, report.Deleted)
+}
+
+type recordingObserver struct {
+	evaluated []string
+	deleted   []string
+	rollovers []string
+	summaries []RunSummary
+}
+
+func (r *recordingObserver) IndexEvaluated(index Index) {
+	r.evaluated = append(r.evaluated, index.Name)
+}
+func (r *recordingObserver) IndexDeleted(index Index) { r.deleted = append(r.deleted, index.Name) }
+func (r *recordingObserver) RolloverPerformed(index Index) {
+	r.rollovers = append(r.rollovers, index.Name)
+}
+func (r *recordingObserver) RunCompleted(summary RunSummary) {
+	r.summaries = append(r.summaries, summary)
+}
+
+func TestCleaner_Run_Observer(t *testing.T) {
+	now := time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)
+	client := &fakeIndicesClient{
+		indices: []Index{
+			{Name: "jaeger-span-2021-01-01", CreationTime: now.Add(-9 * 24 * time.Hour)},
+			{Name: "jaeger-span-2021-01-09", CreationTime: now.Add(-1 * 24 * time.Hour)},
+			{Name: "jaeger-span-000001", CreationTime: now.Add(-9 * 24 * time.Hour), Aliases: []string{"jaeger-span-write"}},
+		},
+	}
+	observer := &recordingObserver{}
+
+	report, err := New(client).Run(context.Background(), Config{
+		IndexPrefix: "jaeger-",
+		Rollover:    true,
+		MaxAge:      7 * 24 * time.Hour,
+		Clock:       func() time.Time { return now },
+		Observer:    observer,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jaeger-span-000001"}, report.Deleted)
+	assert.Equal(t, []string{"jaeger-span-000001"}, observer.rollovers)
+	assert.ElementsMatch(t, []string{"jaeger-span-2021-01-01", "jaeger-span-2021-01-09", "jaeger-span-000001"}, observer.evaluated)
+	assert.Equal(t, []string{"jaeger-span-000001"}, observer.deleted)
+	require.Len(t, observer.summaries, 1)
+	assert.Equal(t, 1, observer.summaries[0].Deleted)
+	assert.Equal(t, 0, observer.summaries[0].Errors)
+}
+
+func TestCleaner_Run_Observer_RolloverPerformedOnlyOnDeletion(t *testing.T) {
+	now := time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)
+	client := &fakeIndicesClient{
+		indices: []Index{
+			{Name: "jaeger-span-000002", CreationTime: now.Add(-1 * 24 * time.Hour), Aliases: []string{"jaeger-span-write"}},
+		},
+	}
+	observer := &recordingObserver{}
+
+	report, err := New(client).Run(context.Background(), Config{
+		IndexPrefix: "jaeger-",
+		Rollover:    true,
+		MaxAge:      7 * 24 * time.Hour,
+		Clock:       func() time.Time { return now },
+		Observer:    observer,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, report.Deleted)
+	assert.Empty(t, observer.rollovers, "too-young rollover index must not be reported as performed")
+}
+
+func TestCleaner_Run_DeleteError(t *testing.T) {
+	now := time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC)
+	client := &fakeIndicesClient{
+		indices: []Index{
+			{Name: "jaeger-span-2021-01-01", CreationTime: now.Add(-9 * 24 * time.Hour)},
+		},
+		deleteErrs: map[string]error{"jaeger-span-2021-01-01": errors.New("boom")},
+	}
+
+	report, err := New(client).Run(context.Background(), Config{
+		IndexPrefix: "jaeger-",
+		MaxAge:      7 * 24 * time.Hour,
+		Clock:       func() time.Time { return now },
+	})
+	require.NoError(t, err)
+	assert.Empty(t, report.Deleted)
+	require.EqualError(t, report.Errors["jaeger-span-2021-01-01"], "boom")
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cleanermetricsprom implements a cleanermetrics.Factory backed by
+// Prometheus collectors, so cleanup activity can be scraped or pushed to a
+// Pushgateway like any other Prometheus metric.
+package cleanermetricsprom
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/cleanermetrics"
+)
+
+// Factory is a cleanermetrics.Factory that registers a counter or histogram
+// per metric name against its own prometheus.Registry, so callers can choose
+// how to expose it (a scrape endpoint, a Pushgateway push, etc.) without the
+// Factory knowing about any of them.
+type Factory struct {
+	registry *prometheus.Registry
+}
+
+// NewFactory returns a Factory backed by a fresh prometheus.Registry.
+func NewFactory() *Factory {
+	return &Factory{registry: prometheus.NewRegistry()}
+}
+
+// Registry returns the prometheus.Registry the Factory's collectors are
+// registered against, e.g. for scraping or for pushing to a Pushgateway.
+func (f *Factory) Registry() *prometheus.Registry {
+	return f.registry
+}
+
+func (f *Factory) Counter(name, help string) cleanermetrics.Counter {
+	c := prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: help})
+	f.registry.MustRegister(c)
+	return counter{c}
+}
+
+func (f *Factory) Timer(name, help string) cleanermetrics.Timer {
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: name, Help: help})
+	f.registry.MustRegister(h)
+	return timer{h}
+}
+
+type counter struct {
+	c prometheus.Counter
+}
+
+func (c counter) Inc(delta int64) { c.c.Add(float64(delta)) }
+
+type timer struct {
+	h prometheus.Histogram
+}
+
+func (t timer) Record(d time.Duration) { t.h.Observe(d.Seconds()) }
@@ -0,0 +1,48 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleanermetricsprom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactory_Counter(t *testing.T) {
+	f := NewFactory()
+	c := f.Counter("index_cleaner_indices_deleted", "indices deleted")
+	c.Inc(3)
+	c.Inc(2)
+
+	mfs, err := f.Registry().Gather()
+	require.NoError(t, err)
+	require.Len(t, mfs, 1)
+	assert.Equal(t, "index_cleaner_indices_deleted", mfs[0].GetName())
+	assert.Equal(t, float64(5), mfs[0].GetMetric()[0].GetCounter().GetValue())
+}
+
+func TestFactory_Timer(t *testing.T) {
+	f := NewFactory()
+	timer := f.Timer("index_cleaner_run_duration", "run duration")
+	timer.Record(5 * time.Second)
+
+	mfs, err := f.Registry().Gather()
+	require.NoError(t, err)
+	require.Len(t, mfs, 1)
+	assert.Equal(t, "index_cleaner_run_duration", mfs[0].GetName())
+	assert.Equal(t, uint64(1), mfs[0].GetMetric()[0].GetHistogram().GetSampleCount())
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cleanermetricstest provides an in-memory cleanermetrics.Factory for
+// use in tests, so callers that exercise cleanermetrics.NewObserver don't
+// need a real metrics backend wired up.
+package cleanermetricstest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/cleanermetrics"
+)
+
+// Factory is an in-memory cleanermetrics.Factory that records every counter
+// increment, for assertion in tests.
+type Factory struct {
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+// NewFactory returns a ready-to-use Factory. The backlog argument is accepted
+// for parity with other Jaeger test factories but is unused here.
+func NewFactory(_ time.Duration) *Factory {
+	return &Factory{counters: make(map[string]int64)}
+}
+
+// Stop is a no-op, kept for parity with other Jaeger test factories.
+func (f *Factory) Stop() {}
+
+// Counter returns a counter that accumulates into this Factory.
+func (f *Factory) Counter(name, _ string) cleanermetrics.Counter {
+	return &counter{factory: f, name: name}
+}
+
+// Timer returns a Timer that discards every recorded duration.
+func (f *Factory) Timer(_, _ string) cleanermetrics.Timer {
+	return nopTimer{}
+}
+
+// ExpectedMetric is one assertion made by AssertCounterMetrics.
+type ExpectedMetric struct {
+	Name  string
+	Value int64
+}
+
+// AssertCounterMetrics fails t unless every expected counter has the given value.
+func (f *Factory) AssertCounterMetrics(t *testing.T, expected ...ExpectedMetric) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, e := range expected {
+		assert.Equal(t, e.Value, f.counters[e.Name], "counter %s", e.Name)
+	}
+}
+
+type counter struct {
+	factory *Factory
+	name    string
+}
+
+func (c *counter) Inc(delta int64) {
+	c.factory.mu.Lock()
+	defer c.factory.mu.Unlock()
+	c.factory.counters[c.name] += delta
+}
+
+type nopTimer struct{}
+
+func (nopTimer) Record(time.Duration) {}
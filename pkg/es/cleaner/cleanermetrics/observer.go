@@ -0,0 +1,73 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cleanermetrics implements a cleaner.Observer backed by a minimal
+// counter/timer Factory abstraction, so index cleanup activity can be wired
+// into whatever metrics backend (e.g. Prometheus) a caller's Factory
+// implementation exports to.
+package cleanermetrics
+
+import (
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner"
+)
+
+// Counter tracks a monotonically increasing count.
+type Counter interface {
+	Inc(delta int64)
+}
+
+// Timer tracks the distribution of durations.
+type Timer interface {
+	Record(d time.Duration)
+}
+
+// Factory creates the named counters and timers an Observer records through.
+type Factory interface {
+	Counter(name, help string) Counter
+	Timer(name, help string) Timer
+}
+
+type observer struct {
+	indicesEvaluated Counter
+	indicesDeleted   Counter
+	rolloversSeen    Counter
+	runsCompleted    Counter
+	runErrors        Counter
+	runDuration      Timer
+}
+
+// NewObserver returns a cleaner.Observer that records cleanup activity
+// through the given Factory.
+func NewObserver(factory Factory) cleaner.Observer {
+	return &observer{
+		indicesEvaluated: factory.Counter("index_cleaner_indices_evaluated", "Number of indices considered during index cleaner runs"),
+		indicesDeleted:   factory.Counter("index_cleaner_indices_deleted", "Number of indices deleted by index cleaner runs"),
+		rolloversSeen:    factory.Counter("index_cleaner_rollovers_performed", "Number of rollover-aliased indices deleted by index cleaner runs"),
+		runsCompleted:    factory.Counter("index_cleaner_runs_completed", "Number of index cleaner runs completed"),
+		runErrors:        factory.Counter("index_cleaner_run_errors", "Number of index deletion errors across index cleaner runs"),
+		runDuration:      factory.Timer("index_cleaner_run_duration", "Duration of an index cleaner run"),
+	}
+}
+
+func (o *observer) IndexEvaluated(cleaner.Index)    { o.indicesEvaluated.Inc(1) }
+func (o *observer) IndexDeleted(cleaner.Index)      { o.indicesDeleted.Inc(1) }
+func (o *observer) RolloverPerformed(cleaner.Index) { o.rolloversSeen.Inc(1) }
+
+func (o *observer) RunCompleted(summary cleaner.RunSummary) {
+	o.runsCompleted.Inc(1)
+	o.runErrors.Inc(int64(summary.Errors))
+	o.runDuration.Record(summary.Duration)
+}
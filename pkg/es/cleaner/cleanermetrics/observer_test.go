@@ -0,0 +1,44 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleanermetrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner"
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/cleanermetrics"
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/cleanermetrics/cleanermetricstest"
+)
+
+func TestObserver(t *testing.T) {
+	mf := cleanermetricstest.NewFactory(0)
+	defer mf.Stop()
+	observer := cleanermetrics.NewObserver(mf)
+
+	observer.IndexEvaluated(cleaner.Index{Name: "jaeger-span-2021-01-01"})
+	observer.IndexEvaluated(cleaner.Index{Name: "jaeger-span-2021-01-02"})
+	observer.IndexDeleted(cleaner.Index{Name: "jaeger-span-2021-01-01"})
+	observer.RolloverPerformed(cleaner.Index{Name: "jaeger-span-000001"})
+	observer.RunCompleted(cleaner.RunSummary{Duration: 5 * time.Second, Deleted: 1, Errors: 0})
+
+	mf.AssertCounterMetrics(t,
+		cleanermetricstest.ExpectedMetric{Name: "index_cleaner_indices_evaluated", Value: 2},
+		cleanermetricstest.ExpectedMetric{Name: "index_cleaner_indices_deleted", Value: 1},
+		cleanermetricstest.ExpectedMetric{Name: "index_cleaner_rollovers_performed", Value: 1},
+		cleanermetricstest.ExpectedMetric{Name: "index_cleaner_runs_completed", Value: 1},
+		cleanermetricstest.ExpectedMetric{Name: "index_cleaner_run_errors", Value: 0},
+	)
+}
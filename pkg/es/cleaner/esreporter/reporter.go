@@ -0,0 +1,106 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package esreporter implements a cleaner.Observer that indexes a summary
+// document for every completed run into Elasticsearch/OpenSearch, analogous
+// to the ESReporter pattern used by the external trace reporter. This lets
+// operators dashboard index-cleanup activity in Kibana/Grafana over time.
+package esreporter
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner"
+)
+
+const defaultIndexPrefix = "jaeger-index-cleaner-runs-"
+
+// Indexer is the subset of an Elasticsearch/OpenSearch client the Reporter
+// needs to write run summary documents.
+type Indexer interface {
+	Index(ctx context.Context, index string, document interface{}) error
+}
+
+// runDocument is what gets indexed for every completed run.
+type runDocument struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Duration  time.Duration     `json:"duration"`
+	Deleted   int               `json:"deleted"`
+	Errors    int               `json:"errors"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// Reporter is a cleaner.Observer that writes a runDocument to Elasticsearch/
+// OpenSearch whenever a run completes; it ignores the per-index events.
+type Reporter struct {
+	cleaner.NopObserver
+
+	indexer     Indexer
+	indexPrefix string
+	tags        map[string]string
+	logger      *zap.Logger
+}
+
+// Option configures a Reporter.
+type Option func(*Reporter)
+
+// WithIndexPrefix overrides the default "jaeger-index-cleaner-runs-" prefix.
+func WithIndexPrefix(prefix string) Option {
+	return func(r *Reporter) { r.indexPrefix = prefix }
+}
+
+// WithTags attaches static labels (e.g. cluster, environment, jaeger version)
+// to every run document this Reporter writes.
+func WithTags(tags map[string]string) Option {
+	return func(r *Reporter) { r.tags = tags }
+}
+
+// WithLogger overrides the default no-op logger used to report indexing failures.
+func WithLogger(logger *zap.Logger) Option {
+	return func(r *Reporter) { r.logger = logger }
+}
+
+// NewReporter returns a Reporter that writes run summaries through indexer.
+func NewReporter(indexer Indexer, opts ...Option) *Reporter {
+	r := &Reporter{
+		indexer:     indexer,
+		indexPrefix: defaultIndexPrefix,
+		logger:      zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RunCompleted indexes a document summarizing the run. Since cleaner.Observer
+// does not allow returning an error, indexing failures are logged rather than
+// propagated.
+func (r *Reporter) RunCompleted(summary cleaner.RunSummary) {
+	now := time.Now()
+	doc := runDocument{
+		Timestamp: now,
+		Duration:  summary.Duration,
+		Deleted:   summary.Deleted,
+		Errors:    summary.Errors,
+		Tags:      r.tags,
+	}
+	index := r.indexPrefix + now.UTC().Format("2006-01-02")
+	if err := r.indexer.Index(context.Background(), index, doc); err != nil {
+		r.logger.Error("failed to index cleaner run summary", zap.String("index", index), zap.Error(err))
+	}
+}
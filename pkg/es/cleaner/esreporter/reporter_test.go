@@ -0,0 +1,61 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package esreporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner"
+)
+
+type fakeIndexer struct {
+	index    string
+	document interface{}
+}
+
+func (f *fakeIndexer) Index(_ context.Context, index string, document interface{}) error {
+	f.index = index
+	f.document = document
+	return nil
+}
+
+func TestReporter_RunCompleted(t *testing.T) {
+	indexer := &fakeIndexer{}
+	reporter := NewReporter(indexer, WithTags(map[string]string{"cluster": "prod"}))
+
+	reporter.RunCompleted(cleaner.RunSummary{Duration: 3 * time.Second, Deleted: 2, Errors: 1})
+
+	require.NotNil(t, indexer.document)
+	assert.Contains(t, indexer.index, defaultIndexPrefix)
+	doc, ok := indexer.document.(runDocument)
+	require.True(t, ok)
+	assert.Equal(t, 2, doc.Deleted)
+	assert.Equal(t, 1, doc.Errors)
+	assert.Equal(t, "prod", doc.Tags["cluster"])
+}
+
+func TestReporter_IgnoresPerIndexEvents(t *testing.T) {
+	reporter := NewReporter(&fakeIndexer{})
+	assert.NotPanics(t, func() {
+		reporter.IndexEvaluated(cleaner.Index{Name: "jaeger-span-2021-01-01"})
+		reporter.IndexDeleted(cleaner.Index{Name: "jaeger-span-2021-01-01"})
+		reporter.RolloverPerformed(cleaner.Index{Name: "jaeger-span-000001"})
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleaner
+
+import "time"
+
+// Observer receives events as a Run progresses, so that callers can plug in
+// their own cleanup-activity sinks (metrics, audit logs, dashboards) without
+// the Cleaner knowing about any of them.
+type Observer interface {
+	// IndexEvaluated is called for every index that matches Config.IndexPrefix,
+	// before the decision to delete or skip it is made.
+	IndexEvaluated(index Index)
+	// IndexDeleted is called after an index has been successfully deleted.
+	IndexDeleted(index Index)
+	// RolloverPerformed is called after a rollover-aliased index has been
+	// successfully deleted while Config.Rollover is set; it is a companion
+	// to IndexDeleted, not a weaker "considered" signal.
+	RolloverPerformed(index Index)
+	// RunCompleted is called once at the end of Run, regardless of outcome.
+	RunCompleted(summary RunSummary)
+}
+
+// RunSummary describes the outcome of a single Run, passed to RunCompleted.
+type RunSummary struct {
+	Duration time.Duration
+	Deleted  int
+	Errors   int
+}
+
+// NopObserver is an Observer that does nothing. It is the default used when
+// Config.Observer is nil, and a convenient base to embed for observers that
+// only care about a subset of the events.
+type NopObserver struct{}
+
+func (NopObserver) IndexEvaluated(Index)    {}
+func (NopObserver) IndexDeleted(Index)      {}
+func (NopObserver) RolloverPerformed(Index) {}
+func (NopObserver) RunCompleted(RunSummary) {}
+
+// multiObserver fans events out to several Observers, e.g. a metrics
+// collector and an Elasticsearch-writing reporter running side by side.
+type multiObserver []Observer
+
+// NewMultiObserver returns an Observer that forwards every event to each of
+// the given observers, in order.
+func NewMultiObserver(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
+
+func (m multiObserver) IndexEvaluated(index Index) {
+	for _, o := range m {
+		o.IndexEvaluated(index)
+	}
+}
+
+func (m multiObserver) IndexDeleted(index Index) {
+	for _, o := range m {
+		o.IndexDeleted(index)
+	}
+}
+
+func (m multiObserver) RolloverPerformed(index Index) {
+	for _, o := range m {
+		o.RolloverPerformed(index)
+	}
+}
+
+func (m multiObserver) RunCompleted(summary RunSummary) {
+	for _, o := range m {
+		o.RunCompleted(summary)
+	}
+}
@@ -19,15 +19,29 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/olivere/elastic"
 	olivere7 "github.com/olivere/elastic/v7"
+	opensearch "github.com/opensearch-project/opensearch-go"
+	opensearchapi "github.com/opensearch-project/opensearch-go/opensearchapi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"runtime"
+
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner"
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/cleanermetrics"
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/cleanermetrics/cleanermetricstest"
+	"github.com/jaegertracing/jaeger/pkg/es/cleaner/esreporter"
 )
 
 const (
@@ -38,22 +52,27 @@ const (
 	indexCleanerImage     = "jaegertracing/jaeger-es-index-cleaner:latest"
 	rolloverImage         = "jaegertracing/jaeger-es-rollover:latest"
 	rolloverNowEnvVar     = `CONDITIONS='{"max_age":"0s"}'`
+	useOpenSearchEnvVar   = "USE_OPENSEARCH"
 )
 
+// useOpenSearch reports whether the integration suite should run against an
+// OpenSearch cluster instead of Elasticsearch, mirroring the USE_OPEN_SEARCH
+// pattern used by the external reporter code.
+func useOpenSearch() bool {
+	use, _ := strconv.ParseBool(os.Getenv(useOpenSearchEnvVar))
+	return use
+}
+
 type esClient struct {
-	client  *elastic.Client
-	client7 *olivere7.Client
+	client       *elastic.Client
+	client7      *olivere7.Client
+	openSearchCl *opensearch.Client
 }
 
 func TestIndexCleaner_doNotFailOnEmptyStorage(t *testing.T) {
 	client, err := createESClient()
 	require.NoError(t, err)
-	if client.client != nil {
-		_, err = client.client.DeleteIndex("*").Do(context.Background())
-	} else {
-		_, err = client.client7.DeleteIndex("*").Do(context.Background())
-	}
-	require.NoError(t, err)
+	require.NoError(t, client.deleteAllIndices())
 
 	tests := []struct {
 		envs []string
@@ -63,7 +82,7 @@ func TestIndexCleaner_doNotFailOnEmptyStorage(t *testing.T) {
 		{envs: []string{"ARCHIVE=true"}},
 	}
 	for _, test := range tests {
-		err := runEsCleaner(7, test.envs)
+		_, err := runIndexCleanerLib(client, 7, test.envs, nil)
 		require.NoError(t, err)
 	}
 }
@@ -79,19 +98,62 @@ func TestIndexCleaner_doNotFailOnFullStorage(t *testing.T) {
 		{envs: []string{"ARCHIVE=true"}},
 	}
 	for _, test := range tests {
-		if client.client != nil {
-			_, err = client.client.DeleteIndex("*").Do(context.Background())
-		} else {
-			_, err = client.client7.DeleteIndex("*").Do(context.Background())
-		}
-		require.NoError(t, err)
+		require.NoError(t, client.deleteAllIndices())
 		err := createAllIndices(client, "")
 		require.NoError(t, err)
-		err = runEsCleaner(1500, test.envs)
+		_, err = runIndexCleanerLib(client, 1500, test.envs, nil)
 		require.NoError(t, err)
 	}
 }
 
+// TestIndexCleaner_dockerImageSmoke is the one remaining test that exercises
+// the jaeger-es-index-cleaner Docker image directly, so we keep CI coverage
+// that the published image still honors the CLI/env contract that
+// pkg/es/cleaner and cmd/jaeger-es-index-cleaner implement in-process.
+func TestIndexCleaner_dockerImageSmoke(t *testing.T) {
+	client, err := createESClient()
+	require.NoError(t, err)
+	require.NoError(t, client.deleteAllIndices())
+	require.NoError(t, createAllIndices(client, ""))
+	require.NoError(t, runEsCleaner(0, nil))
+}
+
+// TestIndexCleaner_observers verifies that, after a cleanup run, both a
+// metrics sink and an Elasticsearch-writing reporter sink observed the
+// expected events.
+func TestIndexCleaner_observers(t *testing.T) {
+	client, err := createESClient()
+	require.NoError(t, err)
+	require.NoError(t, client.deleteAllIndices())
+	require.NoError(t, createAllIndices(client, ""))
+
+	mf := cleanermetricstest.NewFactory(0)
+	defer mf.Stop()
+	indexer := &fakeRunIndexer{}
+	observer := cleaner.NewMultiObserver(
+		cleanermetrics.NewObserver(mf),
+		esreporter.NewReporter(indexer),
+	)
+
+	_, err = runIndexCleanerLib(client, 0, nil, observer)
+	require.NoError(t, err)
+
+	mf.AssertCounterMetrics(t, cleanermetricstest.ExpectedMetric{Name: "index_cleaner_runs_completed", Value: 1})
+	require.NotNil(t, indexer.document)
+	assert.Contains(t, indexer.index, "jaeger-index-cleaner-runs-")
+}
+
+type fakeRunIndexer struct {
+	index    string
+	document interface{}
+}
+
+func (f *fakeRunIndexer) Index(_ context.Context, index string, document interface{}) error {
+	f.index = index
+	f.document = document
+	return nil
+}
+
 func TestIndexCleaner(t *testing.T) {
 	client, err := createESClient()
 	require.NoError(t, err)
@@ -141,30 +203,19 @@ func TestIndexCleaner(t *testing.T) {
 
 func runIndexCleanerTest(t *testing.T, client esClient, prefix string, expectedIndices, envVars []string) {
 	// make sure ES is clean
-	var err error
-	if client.client != nil {
-		_, err = client.client.DeleteIndex("*").Do(context.Background())
-	} else {
-		_, err = client.client7.DeleteIndex("*").Do(context.Background())
-	}
-	require.NoError(t, err)
+	require.NoError(t, client.deleteAllIndices())
 
-	err = createAllIndices(client, prefix)
+	err := createAllIndices(client, prefix)
 	if err != nil {
 		fmt.Println("Hell : ", err.Error())
 		_, filename, line, _ := runtime.Caller(0)
 		fmt.Printf("[error] %s:%d %v for %s \n", filename, line, err, prefix)
 	}
 	require.NoError(t, err)
-	err = runEsCleaner(0, envVars)
+	_, err = runIndexCleanerLib(client, 0, envVars, nil)
 	require.NoError(t, err)
 
-	var indices []string
-	if client.client != nil {
-		indices, err = client.client.IndexNames()
-	} else {
-		indices, err = client.client7.IndexNames()
-	}
+	indices, err := client.indexNames()
 	require.NoError(t, err)
 	if prefix != "" {
 		prefix = prefix + "-"
@@ -222,24 +273,84 @@ func createAllIndices(client esClient, prefix string) error {
 
 func createEsIndices(client esClient, indices []string) error {
 	for _, index := range indices {
-		if client.client != nil {
-			if _, err := client.client.CreateIndex(index).Do(context.Background()); err != nil {
-				_, filename, line, _ := runtime.Caller(1)
-				fmt.Printf("[error] %s:%d %v for %s \n", filename, line, err, index)
-				return err
-			}
-		} else {
-			if _, err := client.client7.CreateIndex(index).Do(context.Background()); err != nil {
-				_, filename, line, _ := runtime.Caller(1)
-				fmt.Printf("[error] %s:%d %v for %s \n", filename, line, err, index)
-				return err
-			}
+		if err := client.createIndex(index); err != nil {
+			_, filename, line, _ := runtime.Caller(1)
+			fmt.Printf("[error] %s:%d %v for %s \n", filename, line, err, index)
+			return err
 		}
 	}
 	return nil
 }
 
+// deleteAllIndices wipes every index on whichever backend this client was
+// created for (Elasticsearch 5/6, Elasticsearch 7, or OpenSearch).
+func (c esClient) deleteAllIndices() error {
+	switch {
+	case c.openSearchCl != nil:
+		res, err := opensearchapi.IndicesDeleteRequest{Index: []string{"*"}}.Do(context.Background(), c.openSearchCl)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		return nil
+	case c.client7 != nil:
+		_, err := c.client7.DeleteIndex("*").Do(context.Background())
+		return err
+	default:
+		_, err := c.client.DeleteIndex("*").Do(context.Background())
+		return err
+	}
+}
+
+// indexNames returns the names of every index currently present on the backend.
+func (c esClient) indexNames() ([]string, error) {
+	switch {
+	case c.openSearchCl != nil:
+		res, err := opensearchapi.CatIndicesRequest{Format: "json"}.Do(context.Background(), c.openSearchCl)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		var entries []struct {
+			Index string `json:"index"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Index)
+		}
+		return names, nil
+	case c.client7 != nil:
+		return c.client7.IndexNames()
+	default:
+		return c.client.IndexNames()
+	}
+}
+
+func (c esClient) createIndex(index string) error {
+	switch {
+	case c.openSearchCl != nil:
+		res, err := opensearchapi.IndicesCreateRequest{Index: index}.Do(context.Background(), c.openSearchCl)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		return nil
+	case c.client7 != nil:
+		_, err := c.client7.CreateIndex(index).Do(context.Background())
+		return err
+	default:
+		_, err := c.client.CreateIndex(index).Do(context.Background())
+		return err
+	}
+}
+
 func runEsCleaner(days int, envs []string) error {
+	if useOpenSearch() {
+		envs = append(envs, useOpenSearchEnvVar+"=true")
+	}
 	var dockerEnv string
 	for _, e := range envs {
 		dockerEnv += fmt.Sprintf(" -e %s", e)
@@ -252,6 +363,9 @@ func runEsCleaner(days int, envs []string) error {
 }
 
 func runEsRollover(action string, envs []string) error {
+	if useOpenSearch() {
+		envs = append(envs, useOpenSearchEnvVar+"=true")
+	}
 	var dockerEnv string
 	for _, e := range envs {
 		dockerEnv += fmt.Sprintf(" -e %s", e)
@@ -263,7 +377,21 @@ func runEsRollover(action string, envs []string) error {
 	return err
 }
 
+// createESClient builds a client for whichever backend the integration suite
+// is configured to run against. When USE_OPENSEARCH is set, it speaks the
+// OpenSearch REST API (1.x/2.x); otherwise it falls back to the existing
+// Elasticsearch 6/7 detection.
 func createESClient() (esClient, error) {
+	if useOpenSearch() {
+		cl, err := opensearch.NewClient(opensearch.Config{
+			Addresses: []string{queryURL},
+		})
+		if err != nil {
+			return esClient{}, err
+		}
+		return esClient{openSearchCl: cl}, nil
+	}
+
 	s := &ESStorageIntegration{}
 	cl, err := elastic.NewClient(
 		elastic.SetURL(queryURL),
@@ -287,3 +415,179 @@ func createESClient() (esClient, error) {
 		client7: nil,
 	}, err
 }
+
+// runIndexCleanerLib runs the index cleanup in-process, through
+// pkg/es/cleaner, translating the same ROLLOVER/ARCHIVE/INDEX_PREFIX envs
+// the Docker image understands into a cleaner.Config.
+func runIndexCleanerLib(client esClient, days int, envs []string, observer cleaner.Observer) (cleaner.Report, error) {
+	cfg := cleaner.Config{MaxAge: time.Duration(days) * 24 * time.Hour, Observer: observer}
+	for _, e := range envs {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "ROLLOVER":
+			cfg.Rollover = kv[1] == "true"
+		case "ARCHIVE":
+			cfg.Archive = kv[1] == "true"
+		case "INDEX_PREFIX":
+			cfg.IndexPrefix = kv[1] + "-"
+		}
+	}
+	return cleaner.New(cleanerIndicesClient{client}).Run(context.Background(), cfg)
+}
+
+// cleanerIndicesClient adapts esClient to cleaner.IndicesClient so the
+// integration tests can drive the cleaner library directly instead of
+// shelling out to the jaeger-es-index-cleaner Docker image.
+type cleanerIndicesClient struct {
+	client esClient
+}
+
+func (c cleanerIndicesClient) GetIndices(ctx context.Context) ([]cleaner.Index, error) {
+	names, err := c.client.indexNames()
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]cleaner.Index, 0, len(names))
+	for _, name := range names {
+		created, aliases, err := c.client.indexMeta(ctx, name)
+		if err != nil {
+			if errors.Is(err, errMalformedCreationDate) {
+				log.Printf("skipping index %s: %v", name, err)
+				continue
+			}
+			return nil, err
+		}
+		indices = append(indices, cleaner.Index{
+			Name:         name,
+			CreationTime: created,
+			Aliases:      aliases,
+		})
+	}
+	return indices, nil
+}
+
+func (c cleanerIndicesClient) DeleteIndex(ctx context.Context, name string) error {
+	switch {
+	case c.client.openSearchCl != nil:
+		res, err := opensearchapi.IndicesDeleteRequest{Index: []string{name}}.Do(ctx, c.client.openSearchCl)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		return nil
+	case c.client.client7 != nil:
+		_, err := c.client.client7.DeleteIndex(name).Do(ctx)
+		return err
+	default:
+		_, err := c.client.client.DeleteIndex(name).Do(ctx)
+		return err
+	}
+}
+
+// indexMeta returns an index's creation time and the aliases pointing at it,
+// which the cleaner needs to distinguish rollover (read/write alias) indices
+// from the daily-bucketed ones.
+func (c esClient) indexMeta(ctx context.Context, name string) (time.Time, []string, error) {
+	switch {
+	case c.openSearchCl != nil:
+		res, err := opensearchapi.IndicesGetRequest{Index: []string{name}}.Do(ctx, c.openSearchCl)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		defer res.Body.Close()
+		var body map[string]struct {
+			Aliases  map[string]interface{} `json:"aliases"`
+			Settings struct {
+				Index struct {
+					CreationDate string `json:"creation_date"`
+				} `json:"index"`
+			} `json:"settings"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+			return time.Time{}, nil, err
+		}
+		entry := body[name]
+		created, err := parseCreationDate(entry.Settings.Index.CreationDate)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		return created, aliasNames(entry.Aliases), nil
+	case c.client7 != nil:
+		settings, err := c.client7.IndexGetSettings(name).Do(ctx)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		aliasesResult, err := c.client7.Aliases().Index(name).Do(ctx)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		var creationDate string
+		if setting, ok := settings[name]; ok {
+			if index, ok := setting.Settings["index"].(map[string]interface{}); ok {
+				creationDate, _ = index["creation_date"].(string)
+			}
+		}
+		var aliases []string
+		if info, ok := aliasesResult.Indices[name]; ok {
+			for _, alias := range info.Aliases {
+				aliases = append(aliases, alias.AliasName)
+			}
+		}
+		created, err := parseCreationDate(creationDate)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		return created, aliases, nil
+	default:
+		settings, err := c.client.IndexGetSettings(name).Do(ctx)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		aliasesResult, err := c.client.Aliases().Index(name).Do(ctx)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		var creationDate string
+		if setting, ok := settings[name]; ok {
+			if index, ok := setting.Settings["index"].(map[string]interface{}); ok {
+				creationDate, _ = index["creation_date"].(string)
+			}
+		}
+		var aliases []string
+		if info, ok := aliasesResult.Indices[name]; ok {
+			for _, alias := range info.Aliases {
+				aliases = append(aliases, alias.AliasName)
+			}
+		}
+		created, err := parseCreationDate(creationDate)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		return created, aliases, nil
+	}
+}
+
+// errMalformedCreationDate marks a parseCreationDate failure so GetIndices
+// can skip the offending index instead of treating it as infinitely old
+// (and thus eligible for immediate deletion), mirroring the fix applied to
+// cmd/jaeger-es-index-cleaner's own creation-date parsing.
+var errMalformedCreationDate = errors.New("malformed creation_date")
+
+func parseCreationDate(creationDate string) (time.Time, error) {
+	millis, err := strconv.ParseInt(creationDate, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %q: %v", errMalformedCreationDate, creationDate, err)
+	}
+	return time.UnixMilli(millis), nil
+}
+
+func aliasNames(aliases map[string]interface{}) []string {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	return names
+}